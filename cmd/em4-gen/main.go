@@ -0,0 +1,313 @@
+// Command em4-gen scans internal/model for structs annotated with an
+// `@entity table="..."` doc comment and generates a typed, dialect-aware
+// repository for them under internal/storage/repo. It is invoked via the
+// go:generate directives next to each annotated struct, e.g.:
+//
+//	//go:generate go run ../../cmd/em4-gen -type Song -out ../storage/repo/song_repo.gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+var entityRe = regexp.MustCompile(`@entity\s+table="([^"]+)"`)
+
+type field struct {
+	GoName string
+	DBName string
+	// GoType is the field's Go type as written in model.go, e.g. "uint" or
+	// "time.Time". It drives List's scan: time.Time columns go through
+	// storage.ScanTime because MySQL and SQLite return them as text, not
+	// time.Time.
+	GoType string
+}
+
+type entity struct {
+	GoType  string
+	Table   string
+	Fields  []field // all columns, including the primary key
+	Setters []field // columns written on Insert/Update (primary key excluded)
+	// HasTimeField reports whether any field is a time.Time column, so the
+	// template can skip importing storage (for its ScanTime scanner) when
+	// it isn't needed.
+	HasTimeField bool
+}
+
+func main() {
+	var (
+		modelFile = flag.String("model", "model.go", "path to the model source file, relative to internal/model")
+		typeName  = flag.String("type", "", "name of the struct to generate a repository for")
+		out       = flag.String("out", "", "output file path, relative to internal/model")
+	)
+	flag.Parse()
+
+	if *typeName == "" || *out == "" {
+		fmt.Fprintln(os.Stderr, "em4-gen: -type and -out are required")
+		os.Exit(1)
+	}
+
+	e, err := parseEntity(*modelFile, *typeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "em4-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	src, err := render(e)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "em4-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "em4-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func parseEntity(modelFile, typeName string) (*entity, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, modelFile, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", modelFile, err)
+	}
+
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				continue
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a struct", typeName)
+			}
+
+			doc := gd.Doc
+			if ts.Doc != nil {
+				doc = ts.Doc
+			}
+			if doc == nil {
+				return nil, fmt.Errorf("%s has no @entity doc comment", typeName)
+			}
+
+			m := entityRe.FindStringSubmatch(doc.Text())
+			if m == nil {
+				return nil, fmt.Errorf("%s has no @entity table=\"...\" annotation", typeName)
+			}
+
+			e := &entity{GoType: typeName, Table: m[1]}
+			for _, f := range st.Fields.List {
+				if len(f.Names) == 0 || f.Tag == nil {
+					continue
+				}
+				tag := strings.Trim(f.Tag.Value, "`")
+				dbName := dbTag(tag)
+				if dbName == "" {
+					continue
+				}
+				fl := field{GoName: f.Names[0].Name, DBName: dbName, GoType: typeString(f.Type)}
+				e.Fields = append(e.Fields, fl)
+				if dbName != "id" {
+					e.Setters = append(e.Setters, fl)
+				}
+				if fl.GoType == "time.Time" {
+					e.HasTimeField = true
+				}
+			}
+			return e, nil
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found", typeName)
+}
+
+// typeString renders the subset of type expressions model.go's fields
+// actually use: bare identifiers (uint, string) and single-level selector
+// expressions (time.Time). Anything else yields "", which the template
+// treats like any other non-time.Time field.
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name
+		}
+	}
+	return ""
+}
+
+func dbTag(structTag string) string {
+	const prefix = `db:"`
+	i := strings.Index(structTag, prefix)
+	if i < 0 {
+		return ""
+	}
+	rest := structTag[i+len(prefix):]
+	j := strings.Index(rest, `"`)
+	if j < 0 {
+		return ""
+	}
+	return rest[:j]
+}
+
+func render(e *entity) ([]byte, error) {
+	tmpl := template.Must(template.New("repo").Parse(repoTemplate))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, e); err != nil {
+		return nil, fmt.Errorf("render %s: %w", e.GoType, err)
+	}
+	return buf.Bytes(), nil
+}
+
+const repoTemplate = `// Code generated by em4-gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"em4/internal/model"
+	{{if .HasTimeField}}"em4/internal/storage"
+	{{end}}"em4/internal/storage/dialect"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SQL{{.GoType}}Repo is the generated {{.Table}} repository.
+type SQL{{.GoType}}Repo struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+}
+
+// NewSQL{{.GoType}}Repo builds a {{.GoType}}Repo backed by db using dialect
+// for placeholder style and id-generation strategy.
+func NewSQL{{.GoType}}Repo(db *sql.DB, d dialect.Dialect) *SQL{{.GoType}}Repo {
+	return &SQL{{.GoType}}Repo{db: db, dialect: d}
+}
+
+func (r *SQL{{.GoType}}Repo) builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(r.dialect.Placeholder())
+}
+
+func (r *SQL{{.GoType}}Repo) Insert(ctx context.Context, v model.{{.GoType}}) (uint, error) {
+	insert := r.builder().
+		Insert("{{.Table}}").
+		Columns({{range .Setters}}"{{.DBName}}", {{end}}).
+		Values({{range .Setters}}v.{{.GoName}}, {{end}})
+
+	if r.dialect.SupportsReturning() {
+		query, args, err := insert.Suffix("RETURNING id").ToSql()
+		if err != nil {
+			return 0, err
+		}
+		var id uint
+		if err := r.db.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint(lastID), nil
+}
+
+func (r *SQL{{.GoType}}Repo) Update(ctx context.Context, id uint, v model.{{.GoType}}) error {
+	update := r.builder().Update("{{.Table}}").Where(sq.Eq{"id": id})
+	{{range .Setters}}update = update.Set("{{.DBName}}", v.{{.GoName}})
+	{{end}}
+	query, args, err := update.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *SQL{{.GoType}}Repo) Delete(ctx context.Context, id uint) error {
+	query, args, err := r.builder().Delete("{{.Table}}").Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *SQL{{.GoType}}Repo) FindByID(ctx context.Context, id uint) (*model.{{.GoType}}, error) {
+	rows, err := r.List(ctx, Filter{"id": id}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &rows[0], nil
+}
+
+func (r *SQL{{.GoType}}Repo) List(ctx context.Context, filter Filter, limit, offset int) ([]model.{{.GoType}}, error) {
+	sel := r.builder().
+		Select({{range .Fields}}"{{.DBName}}", {{end}}).
+		From("{{.Table}}")
+
+	if len(filter) > 0 {
+		sel = sel.Where(sq.Eq(filter))
+	}
+	if limit > 0 {
+		sel = sel.Limit(uint64(limit))
+	}
+	if offset > 0 {
+		sel = sel.Offset(uint64(offset))
+	}
+
+	query, args, err := sel.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.{{.GoType}}
+	for rows.Next() {
+		var v model.{{.GoType}}
+		{{range .Fields}}{{if eq .GoType "time.Time"}}var scan{{.GoName}} storage.ScanTime
+		{{end}}{{end}}if err := rows.Scan({{range .Fields}}{{if eq .GoType "time.Time"}}&scan{{.GoName}}, {{else}}&v.{{.GoName}}, {{end}}{{end}}); err != nil {
+			return nil, err
+		}
+		{{range .Fields}}{{if eq .GoType "time.Time"}}v.{{.GoName}} = scan{{.GoName}}.Time
+		{{end}}{{end}}out = append(out, v)
+	}
+	return out, rows.Err()
+}
+`
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntity(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+// @entity table="widgets"
+type Widget struct {
+	ID   uint   ` + "`db:\"id\"`" + `
+	Name string ` + "`db:\"name\"`" + `
+}
+`
+	path := filepath.Join(dir, "widget.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	e, err := parseEntity(path, "Widget")
+	require.NoError(t, err)
+	require.Equal(t, "widgets", e.Table)
+	require.Len(t, e.Fields, 2)
+	require.Len(t, e.Setters, 1)
+	require.Equal(t, "name", e.Setters[0].DBName)
+	require.Equal(t, "uint", e.Fields[0].GoType)
+	require.Equal(t, "string", e.Fields[1].GoType)
+}
+
+func TestParseEntity_CapturesTimeFields(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+import "time"
+
+// @entity table="widgets"
+type Widget struct {
+	ID        uint      ` + "`db:\"id\"`" + `
+	CreatedAt time.Time ` + "`db:\"created_at\"`" + `
+}
+`
+	path := filepath.Join(dir, "widget.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	e, err := parseEntity(path, "Widget")
+	require.NoError(t, err)
+	require.Equal(t, "time.Time", e.Fields[1].GoType)
+}
+
+func TestParseEntity_MissingAnnotation(t *testing.T) {
+	dir := t.TempDir()
+	src := `package model
+
+type Widget struct {
+	ID uint ` + "`db:\"id\"`" + `
+}
+`
+	path := filepath.Join(dir, "widget.go")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+
+	_, err := parseEntity(path, "Widget")
+	require.Error(t, err)
+}
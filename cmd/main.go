@@ -1,47 +1,158 @@
 package main
 
 import (
+	"context"
 	"em4/config"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 
+	"em4/internal/api"
 	"em4/internal/storage"
+	"em4/internal/storage/lyricsprovider"
+	"em4/internal/storage/repo"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
-func run() error {
-	err := godotenv.Load()
+func loadConfig() (config.Config, error) {
+	cfg := config.Config{}
+	if err := godotenv.Load(); err != nil {
+		return cfg, fmt.Errorf("error loading environment: %v", err)
+	}
+	if err := envconfig.Process("", &cfg); err != nil {
+		return cfg, fmt.Errorf("error loading environment: %v", err)
+	}
+	return cfg, nil
+}
+
+// runMigrate implements `em4 migrate up|down|status [target]`. The
+// `create <name>` subcommand is recognised but creates no file; it returns
+// an error.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: em4 migrate up|down|status|create <name>")
+	}
+
+	direction := args[0]
+	if direction == "create" {
+		return fmt.Errorf("em4 migrate create: not supported yet, add a NNNN_<name>.go file under internal/migrations")
+	}
+
+	var target int64
+	if len(args) > 1 {
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid target version %q: %v", args[1], err)
+		}
+		target = v
+	}
+
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("error loading environment: %v", err)
+		return err
 	}
 
-	cfg := config.Config{}
-	if err := envconfig.Process("", &cfg); err != nil {
-		return fmt.Errorf("error loading environment: %v", err)
+	return storage.Migrate(context.Background(), cfg.Storage.Driver, cfg.Storage.Path, direction, target)
+}
+
+// newClassicStorage picks the database/sql-backed Storage constructor for
+// cfg.Driver ("postgres", "mysql" or "sqlite").
+func newClassicStorage(cfg config.Storage, opts ...storage.Option) (*storage.SQLStorage, error) {
+	switch cfg.Driver {
+	case "", "postgres":
+		return storage.NewPostgresStorage(cfg.Path, opts...)
+	case "mysql":
+		return storage.NewMySQLStorage(cfg.Path, opts...)
+	case "sqlite":
+		return storage.NewSQLiteStorage(cfg.Path, opts...)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
 	}
+}
 
-	classic_storage, err := storage.NewPostgresStorage(cfg.Storage.Path)
+// lyricsOptions builds the storage.Options that wire up AddSong's
+// enrichment pipeline from cfg. Enrichment is left disabled if no
+// provider URL is configured.
+func lyricsOptions(cfg config.Lyrics) []storage.Option {
+	if cfg.ProviderURL == "" {
+		return nil
+	}
+
+	provider := lyricsprovider.NewHTTP(lyricsprovider.HTTPConfig{
+		BaseURL:    cfg.ProviderURL,
+		Timeout:    cfg.Timeout,
+		MaxRetries: cfg.MaxRetries,
+	})
+
+	opts := []storage.Option{storage.WithLyricsProvider(provider)}
+	if cfg.Async {
+		opts = append(opts, storage.WithAsyncEnrichment(cfg.AsyncWorkers))
+	}
+	return opts
+}
+
+// startupSongSampleLimit bounds the List call in logStartupSongCount so a
+// large songs table can't turn a startup log line into a full table scan.
+const startupSongSampleLimit = 1000
+
+// logStartupSongCount logs how many songs are currently in the library
+// using repo.SongRepo directly, so the generated repository is exercised
+// against the live database rather than sitting unused. The count is
+// capped at startupSongSampleLimit; past that it is reported as a lower
+// bound rather than an exact total.
+func logStartupSongCount(ctx context.Context, songs repo.SongRepo) {
+	rows, err := songs.List(ctx, nil, startupSongSampleLimit, 0)
 	if err != nil {
-		return fmt.Errorf("error initializing storage: %v", err)
+		fmt.Printf("songRepo: could not count songs at startup: %v\n", err)
+		return
 	}
-	defer classic_storage.Close()
+	if len(rows) == startupSongSampleLimit {
+		fmt.Printf("songRepo: %d+ songs in the library\n", len(rows))
+		return
+	}
+	fmt.Printf("songRepo: %d songs in the library\n", len(rows))
+}
 
-	orm_storage, err := storage.NewORMPostgresStorage(cfg.Storage.Path)
+func run() error {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		return runMigrate(os.Args[2:])
+	}
+
+	cfg, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("error initializing storage: %v", err)
+		return err
 	}
-	defer orm_storage.Close()
 
-	pgx_storage, err := storage.NewPGXStorage(cfg.Storage.Path)
+	// The goose migrations are Postgres-specific DDL; running them against
+	// cfg.Storage.Driver=="mysql"/"sqlite" would issue BIGSERIAL/TIMESTAMPTZ
+	// statements those backends don't understand, so schema management is
+	// only automatic for Postgres. MySQL and SQLite deployments must apply
+	// their own schema out of band.
+	if cfg.Storage.Driver == "" || cfg.Storage.Driver == "postgres" {
+		if err := storage.EnsureSchema(context.Background(), cfg.Storage.Driver, cfg.Storage.Path); err != nil {
+			return fmt.Errorf("error ensuring schema: %v", err)
+		}
+	}
+
+	classic_storage, err := newClassicStorage(cfg.Storage, lyricsOptions(cfg.Lyrics)...)
 	if err != nil {
 		return fmt.Errorf("error initializing storage: %v", err)
 	}
-	defer pgx_storage.Close()
+	defer classic_storage.Close()
+
+	// songRepo is the em4-gen generated per-entity repository, sharing
+	// classic_storage's connection and dialect. The HTTP handler still
+	// depends on the broader Storage interface, not SongRepo directly, but
+	// logStartupSongCount below gives it a real caller and exercises its
+	// generated List query against the live database on every boot.
+	songRepo := repo.NewSQLSongRepo(classic_storage.DB(), classic_storage.Dialect())
+	logStartupSongCount(context.Background(), songRepo)
 
-	// call funcs ...
-	return nil
+	handler := api.NewHandler(classic_storage)
+	return http.ListenAndServe(cfg.HTTP.Addr, handler.Router())
 }
 
 func main() {
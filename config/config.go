@@ -1,9 +1,32 @@
 package config
 
+import "time"
+
 type Config struct {
 	Storage Storage `env:"STORAGE"`
+	HTTP    HTTP    `env:"HTTP"`
+	Lyrics  Lyrics  `env:"LYRICS"`
 }
 
 type Storage struct {
 	Path string `env:"PATH" required:"true"`
+	// Driver selects the SQL backend: "postgres" (default), "mysql" or "sqlite".
+	Driver string `env:"DRIVER" default:"postgres"`
+}
+
+type HTTP struct {
+	Addr string `env:"ADDR" default:":8080"`
+}
+
+// Lyrics configures the external lyrics-enrichment provider AddSong calls
+// when a song is added without verses. ProviderURL is left empty by
+// default, which leaves enrichment disabled.
+type Lyrics struct {
+	ProviderURL string        `env:"PROVIDER_URL"`
+	Timeout     time.Duration `env:"TIMEOUT" default:"5s"`
+	MaxRetries  int           `env:"MAX_RETRIES" default:"2"`
+	// Async runs enrichment on a background worker pool instead of
+	// blocking AddSong on the provider's network call.
+	Async        bool `env:"ASYNC" default:"false"`
+	AsyncWorkers int  `env:"ASYNC_WORKERS" default:"4"`
 }
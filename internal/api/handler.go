@@ -0,0 +1,257 @@
+// Package api wires the Storage interface to HTTP handlers matching
+// api/openapi.yaml. Request and response bodies are the hand-maintained
+// types in types.go, which mirror the spec's schemas.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	storage "em4/internal"
+	"em4/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const defaultLimit = 20
+
+// Handler implements the /songs HTTP API on top of a storage.Storage.
+type Handler struct {
+	storage storage.Storage
+}
+
+// NewHandler builds a Handler backed by s. SQLStorage satisfies
+// storage.Storage for each of its Postgres, MySQL and SQLite backends, so
+// the same Handler works regardless of which one cmd/main.go wires up.
+func NewHandler(s storage.Storage) *Handler {
+	return &Handler{storage: s}
+}
+
+// Router returns the chi router for every endpoint in api/openapi.yaml.
+func (h *Handler) Router() chi.Router {
+	r := chi.NewRouter()
+	r.Post("/songs", h.addSong)
+	r.Get("/songs", h.listSongs)
+	r.Get("/songs/{id}", h.getSong)
+	r.Get("/songs/{id}/lyrics", h.getSongLyrics)
+	r.Patch("/songs/{id}", h.updateSong)
+	r.Delete("/songs/{id}", h.deleteSong)
+	return r
+}
+
+func (h *Handler) addSong(w http.ResponseWriter, r *http.Request) {
+	var req CreateSongRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Group == "" || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "group and name are required")
+		return
+	}
+
+	song := model.Song{
+		Group:       req.Group,
+		Name:        req.Name,
+		Link:        req.Link,
+		ReleaseDate: req.ReleaseDate,
+	}
+
+	id, err := h.storage.AddSong(r.Context(), song, req.Verses)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	song.ID = id
+
+	writeJSON(w, http.StatusCreated, songToDTO(song))
+}
+
+func (h *Handler) listSongs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filters := map[string]string{}
+	for _, key := range []string{"group", "name", "released_after"} {
+		if v := q.Get(key); v != "" {
+			filters[key] = v
+		}
+	}
+
+	limit, offset := pagination(q)
+
+	songs, err := h.storage.GetAllSongs(r.Context(), filters, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dtos := make([]Song, 0, len(songs))
+	for _, s := range songs {
+		dtos = append(dtos, songToDTO(s))
+	}
+	writeJSON(w, http.StatusOK, dtos)
+}
+
+func (h *Handler) getSong(w http.ResponseWriter, r *http.Request) {
+	id, err := songIDFromPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	song, err := h.storage.GetSong(r.Context(), id)
+	if errors.Is(err, storage.ErrSongNotFound) {
+		writeError(w, http.StatusNotFound, "song not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, songToDTO(*song))
+}
+
+func (h *Handler) getSongLyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := songIDFromPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit, offset := pagination(r.URL.Query())
+
+	lyrics, err := h.storage.GetLyrics(r.Context(), id, limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(lyrics) == 0 {
+		if _, err := h.storage.GetSong(r.Context(), id); errors.Is(err, storage.ErrSongNotFound) {
+			writeError(w, http.StatusNotFound, "song not found")
+			return
+		} else if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	verses := make([]Verse, 0, len(lyrics))
+	for _, l := range lyrics {
+		verses = append(verses, Verse{VerseNumber: l.VerseNumber, Text: l.Text})
+	}
+	writeJSON(w, http.StatusOK, verses)
+}
+
+func (h *Handler) updateSong(w http.ResponseWriter, r *http.Request) {
+	id, err := songIDFromPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req UpdateSongRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := h.storage.GetSong(r.Context(), id); errors.Is(err, storage.ErrSongNotFound) {
+		writeError(w, http.StatusNotFound, "song not found")
+		return
+	} else if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	updates := model.SongUpdate{
+		Group:       req.Group,
+		Name:        req.Name,
+		Link:        req.Link,
+		ReleaseDate: req.ReleaseDate,
+		Verses:      req.Verses,
+	}
+
+	err = h.storage.UpdateSong(r.Context(), id, updates)
+	switch {
+	case errors.Is(err, storage.ErrEmptyUpdate):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, storage.ErrSongNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) deleteSong(w http.ResponseWriter, r *http.Request) {
+	id, err := songIDFromPath(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.storage.DeleteSong(r.Context(), id)
+	switch {
+	case errors.Is(err, storage.ErrSongNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func songIDFromPath(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid song id")
+	}
+	return uint(id), nil
+}
+
+func pagination(q map[string][]string) (limit, offset int) {
+	limit = defaultLimit
+	if v := first(q["limit"]); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if v := first(q["offset"]); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+	return limit, offset
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func songToDTO(s model.Song) Song {
+	return Song{
+		ID:          s.ID,
+		Group:       s.Group,
+		Name:        s.Name,
+		Link:        s.Link,
+		ReleaseDate: s.ReleaseDate,
+		InsertedAt:  s.InsertedAt,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, Error{Message: message})
+}
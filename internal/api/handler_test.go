@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"em4/internal/storage"
+	"em4/internal/storage/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) (*Handler, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	s, err := storage.NewSQLStorageForTest(db, dialect.Postgres{})
+	require.NoError(t, err)
+
+	return NewHandler(s), mock
+}
+
+func TestGetSong(t *testing.T) {
+	h, mock := newTestHandler(t)
+
+	mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM "songs" WHERE id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "name", "link", "release_date", "inserted_at"}).
+			AddRow(1, "Muse", "Starlight", "", "2006-01-01T00:00:00Z", "2006-01-01T00:00:00Z"))
+
+	req := httptest.NewRequest(http.MethodGet, "/songs/1", nil)
+	rec := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got Song
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	require.Equal(t, "Starlight", got.Name)
+}
+
+func TestGetSong_StorageError(t *testing.T) {
+	h, mock := newTestHandler(t)
+
+	mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM "songs" WHERE id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnError(sqlmock.ErrCancelled)
+
+	req := httptest.NewRequest(http.MethodGet, "/songs/1", nil)
+	rec := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestAddSong_InvalidBody(t *testing.T) {
+	h, _ := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/songs", strings.NewReader(`{"name":"Starlight"}`))
+	rec := httptest.NewRecorder()
+
+	h.Router().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,48 @@
+package api
+
+// These types are the wire contract described by api/openapi.yaml. They are
+// hand-maintained, not generated: there is no oapi-codegen invocation or
+// go:generate directive wired up yet, so keep them in sync with the spec by
+// hand whenever either one changes.
+
+import "time"
+
+// Song is the wire representation of model.Song.
+type Song struct {
+	ID          uint      `json:"id"`
+	Group       string    `json:"group"`
+	Name        string    `json:"name"`
+	Link        string    `json:"link"`
+	ReleaseDate time.Time `json:"release_date"`
+	InsertedAt  time.Time `json:"inserted_at"`
+}
+
+// Verse is a single lyrics line returned by GET /songs/{id}/lyrics.
+type Verse struct {
+	VerseNumber uint   `json:"verse_number"`
+	Text        string `json:"text"`
+}
+
+// CreateSongRequest is the POST /songs request body.
+type CreateSongRequest struct {
+	Group       string    `json:"group"`
+	Name        string    `json:"name"`
+	Link        string    `json:"link"`
+	ReleaseDate time.Time `json:"release_date"`
+	Verses      []string  `json:"verses"`
+}
+
+// UpdateSongRequest is the PATCH /songs/{id} request body. Empty fields are
+// left unchanged; Verses maps a verse number to its new text.
+type UpdateSongRequest struct {
+	Group       string          `json:"group"`
+	Name        string          `json:"name"`
+	Link        string          `json:"link"`
+	ReleaseDate string          `json:"release_date"`
+	Verses      map[uint]string `json:"verses"`
+}
+
+// Error is the body returned alongside non-2xx responses.
+type Error struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(Up0001, Down0001)
+}
+
+// Up0001 creates the songs and lyrics tables plus the indexes the storage
+// layer relies on for filtering and pagination.
+func Up0001(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE songs (
+			id           BIGSERIAL PRIMARY KEY,
+			group_name   TEXT NOT NULL,
+			name         TEXT NOT NULL,
+			link         TEXT NOT NULL DEFAULT '',
+			release_date DATE NOT NULL,
+			inserted_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE INDEX idx_songs_group_name ON songs (group_name)
+	`); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE INDEX idx_songs_release_date ON songs (release_date)
+	`); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TABLE lyrics (
+			id           BIGSERIAL PRIMARY KEY,
+			song_id      BIGINT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+			verse_number INT NOT NULL,
+			text         TEXT NOT NULL,
+			UNIQUE (song_id, verse_number)
+		)
+	`); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		CREATE INDEX idx_lyrics_song_id ON lyrics (song_id)
+	`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Down0001 drops everything created by Up0001, lyrics first to satisfy the FK.
+func Down0001(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS lyrics`); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DROP TABLE IF EXISTS songs`); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package migrations holds the Go-based goose migrations for the songs
+// library schema. Migrations are registered via init() in sibling files
+// named NNNN_description.go and run in order by storage.Migrate.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.SetBaseFS(nil)
+}
+
+// Run applies or reverts migrations against db. direction is one of
+// "up", "down" or "status"; any other value, "create" included, is
+// rejected with an error. target is the goose version to migrate to; with
+// target 0, "up" applies every pending migration and "down" reverts only
+// the most recently applied one.
+func Run(ctx context.Context, db *sql.DB, direction string, target int64) error {
+	if err := goose.SetDialect("postgres"); err != nil {
+		return fmt.Errorf("migrations: set dialect: %w", err)
+	}
+
+	switch direction {
+	case "up":
+		if target > 0 {
+			return goose.UpToContext(ctx, db, ".", target)
+		}
+		return goose.UpContext(ctx, db, ".")
+	case "down":
+		if target > 0 {
+			return goose.DownToContext(ctx, db, ".", target)
+		}
+		return goose.DownContext(ctx, db, ".")
+	case "status":
+		return goose.StatusContext(ctx, db, ".")
+	default:
+		return fmt.Errorf("migrations: unknown direction %q", direction)
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+//go:generate go run ../../cmd/em4-gen -type Song -out ../storage/repo/song_repo.gen.go
+//go:generate go run ../../cmd/em4-gen -type Lyrics -out ../storage/repo/lyrics_repo.gen.go
+
+// Song is a single track in the library.
+//
+// @entity table="songs"
+type Song struct {
+	ID          uint      `db:"id"`
+	Group       string    `db:"group_name"`
+	Name        string    `db:"name"`
+	Link        string    `db:"link"`
+	ReleaseDate time.Time `db:"release_date"`
+	InsertedAt  time.Time `db:"inserted_at"`
+}
+
+// Lyrics is a single verse belonging to a Song.
+//
+// @entity table="lyrics"
+type Lyrics struct {
+	ID          uint   `db:"id"`
+	SongID      uint   `db:"song_id"`
+	VerseNumber uint   `db:"verse_number"`
+	Text        string `db:"text"`
+}
+
+// SongUpdate carries the partial set of fields to apply to an existing Song.
+// Empty strings mean "leave unchanged"; Verses maps a verse number to its
+// new text and is applied independently of the song fields.
+type SongUpdate struct {
+	Group       string
+	Name        string
+	Link        string
+	ReleaseDate string
+	Verses      map[uint]string
+}
+
+// HasSongFields reports whether any of the scalar Song fields are set.
+func (u SongUpdate) HasSongFields() bool {
+	return u.Group != "" || u.Name != "" || u.Link != "" || u.ReleaseDate != ""
+}
+
+// IsEmpty reports whether the update carries neither song fields nor verses,
+// i.e. there is nothing for UpdateSong to do.
+func (u SongUpdate) IsEmpty() bool {
+	return !u.HasSongFields() && len(u.Verses) == 0
+}
@@ -1,21 +1,27 @@
 package storage
 
 import (
+	"context"
 	"em4/internal/model"
 	"errors"
 )
 
 var (
 	ErrSongNotFound = errors.New("song not found")
+	ErrEmptyUpdate  = errors.New("no fields to update")
+	// ErrUnsupportedMigrationDriver is returned by storage.Migrate when
+	// asked to run the (Postgres-only) goose migrations against a
+	// non-Postgres driver.
+	ErrUnsupportedMigrationDriver = errors.New("migrations only support the postgres driver")
 )
 
 // just for me
 type Storage interface {
-	AddSong(song model.Song, verses []string) (uint, error)
-	DeleteSong(songID uint) error
-	GetLyrics(songID uint, limit, offset int) ([]model.Lyrics, error)
-	GetSong(songID uint) (*model.Song, error)
-	GetAllSongs(filters map[string]string, limit, offset int) ([]model.Song, error)
-	GetAllSongLyrics(songID uint) ([]model.Lyrics, error)
-	UpdateSong(songID uint, updates model.SongUpdate) error
+	AddSong(ctx context.Context, song model.Song, verses []string) (uint, error)
+	DeleteSong(ctx context.Context, songID uint) error
+	GetLyrics(ctx context.Context, songID uint, limit, offset int) ([]model.Lyrics, error)
+	GetSong(ctx context.Context, songID uint) (*model.Song, error)
+	GetAllSongs(ctx context.Context, filters map[string]string, limit, offset int) ([]model.Song, error)
+	GetAllSongLyrics(ctx context.Context, songID uint) ([]model.Lyrics, error)
+	UpdateSong(ctx context.Context, songID uint, updates model.SongUpdate) error
 }
@@ -0,0 +1,58 @@
+// Package dialect isolates the handful of ways Postgres, MySQL and SQLite
+// disagree on SQL syntax so the rest of the storage package can build
+// queries once with Masterminds/squirrel and run them against any of the
+// three backends.
+package dialect
+
+import sq "github.com/Masterminds/squirrel"
+
+// Dialect describes the SQL dialect differences a query builder needs to
+// know about: placeholder style, how to get the id of a freshly inserted
+// row, and how identifiers are quoted.
+type Dialect interface {
+	// Name identifies the dialect, e.g. for logging.
+	Name() string
+
+	// Placeholder is the squirrel placeholder format for this dialect
+	// ($1, ?, ?, ...).
+	Placeholder() sq.PlaceholderFormat
+
+	// SupportsReturning reports whether INSERT ... RETURNING is available.
+	// When false, callers must fall back to sql.Result.LastInsertId.
+	SupportsReturning() bool
+
+	// QuoteIdent quotes a bare identifier (table or column name) for use
+	// in a raw fragment.
+	QuoteIdent(ident string) string
+
+	// Now is the SQL expression for the current timestamp, for use in a
+	// raw fragment such as sq.Expr(d.Now()).
+	Now() string
+}
+
+// Postgres is the dialect for lib/pq and pgx-backed connections.
+type Postgres struct{}
+
+func (Postgres) Name() string                      { return "postgres" }
+func (Postgres) Placeholder() sq.PlaceholderFormat { return sq.Dollar }
+func (Postgres) SupportsReturning() bool           { return true }
+func (Postgres) QuoteIdent(ident string) string    { return `"` + ident + `"` }
+func (Postgres) Now() string                       { return "NOW()" }
+
+// MySQL is the dialect for go-sql-driver/mysql connections.
+type MySQL struct{}
+
+func (MySQL) Name() string                      { return "mysql" }
+func (MySQL) Placeholder() sq.PlaceholderFormat { return sq.Question }
+func (MySQL) SupportsReturning() bool           { return false }
+func (MySQL) QuoteIdent(ident string) string    { return "`" + ident + "`" }
+func (MySQL) Now() string                       { return "NOW()" }
+
+// SQLite is the dialect for mattn/go-sqlite3 connections.
+type SQLite struct{}
+
+func (SQLite) Name() string                      { return "sqlite" }
+func (SQLite) Placeholder() sq.PlaceholderFormat { return sq.Question }
+func (SQLite) SupportsReturning() bool           { return false }
+func (SQLite) QuoteIdent(ident string) string    { return `"` + ident + `"` }
+func (SQLite) Now() string                       { return "CURRENT_TIMESTAMP" }
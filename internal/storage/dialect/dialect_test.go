@@ -0,0 +1,31 @@
+package dialect
+
+import (
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialects(t *testing.T) {
+	cases := []struct {
+		name            string
+		dialect         Dialect
+		wantPlaceholder sq.PlaceholderFormat
+		wantReturning   bool
+		wantQuotedIdent string
+	}{
+		{"postgres", Postgres{}, sq.Dollar, true, `"songs"`},
+		{"mysql", MySQL{}, sq.Question, false, "`songs`"},
+		{"sqlite", SQLite{}, sq.Question, false, `"songs"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.name, tc.dialect.Name())
+			assert.Equal(t, tc.wantPlaceholder, tc.dialect.Placeholder())
+			assert.Equal(t, tc.wantReturning, tc.dialect.SupportsReturning())
+			assert.Equal(t, tc.wantQuotedIdent, tc.dialect.QuoteIdent("songs"))
+		})
+	}
+}
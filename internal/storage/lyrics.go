@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"em4/internal/model"
+	"log"
+	"time"
+)
+
+// LyricsProvider fetches lyrics and release metadata for a track from an
+// external source so AddSong can enrich a song that was added without
+// verses. Concrete implementations live under internal/storage/lyricsprovider
+// (a fake for tests and an HTTP-backed one for production); AddSong only
+// depends on this interface.
+type LyricsProvider interface {
+	// Fetch looks up group/song and returns its verses (already split, in
+	// order), release date and a canonical link, or an error if the track
+	// could not be found or the provider is unavailable.
+	Fetch(ctx context.Context, group, song string) (verses []string, releaseDate time.Time, link string, err error)
+}
+
+// WithLyricsProvider attaches p to a SQLStorage. When AddSong is called
+// with no verses, it calls p.Fetch and persists whatever it returns in the
+// same transaction as the song row. Without this option, a song added with
+// no verses simply has none.
+func WithLyricsProvider(p LyricsProvider) Option {
+	return func(s *SQLStorage) {
+		s.lyricsProvider = p
+	}
+}
+
+// WithAsyncEnrichment makes lyrics enrichment happen on a background worker
+// pool instead of blocking AddSong on the provider's network call. Once a
+// job completes, the fetched verses are written back with UpdateSong; the
+// fetched release date and link are not applied. It has no effect unless
+// WithLyricsProvider is also set.
+func WithAsyncEnrichment(workers int) Option {
+	return func(s *SQLStorage) {
+		s.enrichWorkers = workers
+	}
+}
+
+type enrichJob struct {
+	songID uint
+	group  string
+	name   string
+}
+
+// startEnrichWorkers launches s.enrichWorkers goroutines draining
+// s.enrichQueue. It is a no-op if async enrichment was not requested.
+func (s *SQLStorage) startEnrichWorkers() {
+	if s.enrichWorkers <= 0 {
+		return
+	}
+
+	s.enrichQueue = make(chan enrichJob, 64)
+	for i := 0; i < s.enrichWorkers; i++ {
+		go s.runEnrichWorker()
+	}
+}
+
+func (s *SQLStorage) runEnrichWorker() {
+	for job := range s.enrichQueue {
+		s.enrich(job)
+	}
+}
+
+// enrich fetches lyrics for job and writes them back via UpdateSong. It
+// runs detached from the request that triggered it, so it uses its own
+// background context rather than one tied to an HTTP request's lifetime.
+func (s *SQLStorage) enrich(job enrichJob) {
+	verses, _, _, err := s.lyricsProvider.Fetch(context.Background(), job.group, job.name)
+	if err != nil {
+		log.Printf("enrich song %d: fetch lyrics: %v", job.songID, err)
+		return
+	}
+	if len(verses) == 0 {
+		return
+	}
+
+	versesByNumber := make(map[uint]string, len(verses))
+	for i, verse := range verses {
+		versesByNumber[uint(i+1)] = verse
+	}
+
+	update := model.SongUpdate{Verses: versesByNumber}
+	if err := s.UpdateSong(context.Background(), job.songID, update); err != nil {
+		log.Printf("enrich song %d: update verses: %v", job.songID, err)
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"em4/internal/model"
+	"em4/internal/storage/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLyricsProvider struct {
+	verses      []string
+	releaseDate time.Time
+	link        string
+	err         error
+}
+
+func (f *fakeLyricsProvider) Fetch(context.Context, string, string) ([]string, time.Time, string, error) {
+	return f.verses, f.releaseDate, f.link, f.err
+}
+
+func TestAddSong_EnrichesWhenVersesEmpty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	provider := &fakeLyricsProvider{verses: []string{"fetched verse"}, link: "https://example.com"}
+	s := &SQLStorage{db: db, dialect: dialect.Postgres{}, tracer: noopTracer{}, lyricsProvider: provider}
+
+	song := model.Song{Group: "Muse", Name: "Starlight"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "songs" \(group_name,name,link,release_date,inserted_at\) VALUES \(\$1,\$2,\$3,\$4,NOW\(\)\) RETURNING id`).
+		WithArgs(song.Group, song.Name, "https://example.com", song.ReleaseDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO "lyrics" \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs(1, 1, "fetched verse").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	songID, err := s.AddSong(context.Background(), song, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), songID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddSong_NoProvider_LeavesVersesEmpty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	s := &SQLStorage{db: db, dialect: dialect.Postgres{}, tracer: noopTracer{}}
+	song := model.Song{Group: "Muse", Name: "Starlight"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "songs" \(group_name,name,link,release_date,inserted_at\) VALUES \(\$1,\$2,\$3,\$4,NOW\(\)\) RETURNING id`).
+		WithArgs(song.Group, song.Name, song.Link, song.ReleaseDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	songID, err := s.AddSong(context.Background(), song, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), songID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestEnrich_AsyncJobInsertsVersesThatDidntExistYet drives enrich directly
+// (the function runEnrichWorker calls) against a mock DB to prove an async
+// enrichment job actually lands its verses. AddSong inserts zero lyrics
+// rows for a song enriched asynchronously, so the UpdateSong call enrich
+// makes must INSERT those verses, not silently no-op an UPDATE that
+// matches nothing.
+func TestEnrich_AsyncJobInsertsVersesThatDidntExistYet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	provider := &fakeLyricsProvider{verses: []string{"verse one"}}
+	s := &SQLStorage{db: db, dialect: dialect.Postgres{}, tracer: noopTracer{}, lyricsProvider: provider}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+		WithArgs("verse one", uint(1), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT upsert_verse`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "lyrics" \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\)`).
+		WithArgs(uint(1), uint(1), "verse one").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT upsert_verse`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	s.enrich(enrichJob{songID: 1, group: "Muse", name: "Starlight"})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
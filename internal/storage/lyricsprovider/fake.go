@@ -0,0 +1,45 @@
+package lyricsprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Fake is a storage.LyricsProvider that returns a fixed or per-track result
+// without making any network call. It exists for tests and local
+// development where hitting a real enrichment endpoint is undesirable.
+type Fake struct {
+	// Verses is returned for every call unless Results has an entry for
+	// the requested group/song.
+	Verses      []string
+	ReleaseDate time.Time
+	Link        string
+
+	// Results, when set, overrides Verses/ReleaseDate/Link for a specific
+	// "group/song" key so tests can exercise several tracks at once.
+	Results map[string]FakeResult
+
+	// Err, when set, is returned instead of a result for every call.
+	Err error
+}
+
+// FakeResult is one canned Fetch response for Fake.Results.
+type FakeResult struct {
+	Verses      []string
+	ReleaseDate time.Time
+	Link        string
+}
+
+// Fetch implements storage.LyricsProvider.
+func (f *Fake) Fetch(_ context.Context, group, song string) ([]string, time.Time, string, error) {
+	if f.Err != nil {
+		return nil, time.Time{}, "", f.Err
+	}
+
+	if result, ok := f.Results[fmt.Sprintf("%s/%s", group, song)]; ok {
+		return result.Verses, result.ReleaseDate, result.Link, nil
+	}
+
+	return f.Verses, f.ReleaseDate, f.Link, nil
+}
@@ -0,0 +1,140 @@
+// Package lyricsprovider ships the concrete storage.LyricsProvider
+// implementations: Fake for tests and HTTP for talking to a real
+// metadata/lyrics endpoint.
+package lyricsprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+// HTTPConfig configures an HTTP provider.
+type HTTPConfig struct {
+	// BaseURL is the enrichment service's root, e.g. "https://lyrics.example.com".
+	BaseURL string
+	// Timeout bounds a single attempt, including retries' individual calls.
+	Timeout time.Duration
+	// MaxRetries is how many additional attempts are made after the first
+	// one fails, with exponential backoff between them.
+	MaxRetries int
+}
+
+// HTTP fetches lyrics from a configurable HTTP endpoint. It retries
+// transient failures with backoff and trips a circuit breaker once the
+// endpoint is failing consistently, so a degraded provider can't pile up
+// AddSong latency indefinitely.
+type HTTP struct {
+	baseURL    string
+	client     *http.Client
+	maxRetries int
+	breaker    *gobreaker.CircuitBreaker
+}
+
+// NewHTTP builds an HTTP provider from cfg.
+func NewHTTP(cfg HTTPConfig) *HTTP {
+	return &HTTP{
+		baseURL:    cfg.BaseURL,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		maxRetries: cfg.MaxRetries,
+		breaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name: "lyrics-provider",
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures > 5
+			},
+		}),
+	}
+}
+
+// fetchRequest is the JSON body sent to BaseURL + "/lyrics".
+type fetchRequest struct {
+	Group string `json:"group"`
+	Song  string `json:"song"`
+}
+
+// fetchResponse is the JSON body the endpoint is expected to return.
+type fetchResponse struct {
+	Verses      []string  `json:"verses"`
+	ReleaseDate time.Time `json:"release_date"`
+	Link        string    `json:"link"`
+}
+
+// Fetch implements storage.LyricsProvider.
+func (h *HTTP) Fetch(ctx context.Context, group, song string) ([]string, time.Time, string, error) {
+	result, err := h.breaker.Execute(func() (interface{}, error) {
+		return h.fetchWithRetries(ctx, group, song)
+	})
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+
+	resp := result.(fetchResponse)
+	return resp.Verses, resp.ReleaseDate, resp.Link, nil
+}
+
+func (h *HTTP) fetchWithRetries(ctx context.Context, group, song string) (fetchResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt <= h.maxRetries; attempt++ {
+		resp, err := h.fetchOnce(ctx, group, song)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if attempt < h.maxRetries {
+			select {
+			case <-ctx.Done():
+				return fetchResponse{}, ctx.Err()
+			case <-time.After(retryBackoff(attempt)):
+			}
+		}
+	}
+	return fetchResponse{}, fmt.Errorf("lyrics provider: giving up after %d attempts: %w", h.maxRetries+1, lastErr)
+}
+
+func (h *HTTP) fetchOnce(ctx context.Context, group, song string) (fetchResponse, error) {
+	body, err := json.Marshal(fetchRequest{Group: group, Song: song})
+	if err != nil {
+		return fetchResponse{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+"/lyrics", bytes.NewReader(body))
+	if err != nil {
+		return fetchResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := h.client.Do(req)
+	if err != nil {
+		return fetchResponse{}, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return fetchResponse{}, fmt.Errorf("lyrics provider: unexpected status %d", httpResp.StatusCode)
+	}
+
+	var resp fetchResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return fetchResponse{}, fmt.Errorf("lyrics provider: decode response: %w", err)
+	}
+	return resp, nil
+}
+
+// retryBackoff is the delay before retry attempt n+1 (0-indexed): 100ms,
+// 200ms, 400ms, ... capped at 2s.
+func retryBackoff(attempt int) time.Duration {
+	d := 100 * time.Millisecond
+	for i := 0; i < attempt; i++ {
+		d *= 2
+		if d >= 2*time.Second {
+			return 2 * time.Second
+		}
+	}
+	return d
+}
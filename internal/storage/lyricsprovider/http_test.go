@@ -0,0 +1,67 @@
+package lyricsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Fetch_Success(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req fetchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "Muse", req.Group)
+		assert.Equal(t, "Starlight", req.Song)
+
+		json.NewEncoder(w).Encode(fetchResponse{
+			Verses: []string{"verse one", "verse two"},
+			Link:   "https://example.com/starlight",
+		})
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(HTTPConfig{BaseURL: srv.URL, Timeout: time.Second})
+
+	verses, _, link, err := p.Fetch(context.Background(), "Muse", "Starlight")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"verse one", "verse two"}, verses)
+	assert.Equal(t, "https://example.com/starlight", link)
+}
+
+func TestHTTP_Fetch_RetriesThenSucceeds(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(fetchResponse{Verses: []string{"verse one"}})
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(HTTPConfig{BaseURL: srv.URL, Timeout: time.Second, MaxRetries: 2})
+
+	verses, _, _, err := p.Fetch(context.Background(), "Muse", "Starlight")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"verse one"}, verses)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestHTTP_Fetch_GivesUpAfterMaxRetries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(HTTPConfig{BaseURL: srv.URL, Timeout: time.Second, MaxRetries: 1})
+
+	_, _, _, err := p.Fetch(context.Background(), "Muse", "Starlight")
+	require.Error(t, err)
+}
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	st "em4/internal"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_RejectsNonPostgresDriver(t *testing.T) {
+	for _, driver := range []string{"mysql", "sqlite"} {
+		err := Migrate(context.Background(), driver, "unused", "up", 0)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, st.ErrUnsupportedMigrationDriver))
+	}
+}
@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"em4/internal/model"
+	"em4/internal/storage/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLLyricsRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := NewSQLLyricsRepo(db, dialect.Postgres{})
+
+	verse := model.Lyrics{SongID: 1, VerseNumber: 1, Text: "hello"}
+	mock.ExpectQuery(`INSERT INTO lyrics \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\) RETURNING id`).
+		WithArgs(verse.SongID, verse.VerseNumber, verse.Text).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	id, err := r.Insert(context.Background(), verse)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLLyricsRepo_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := NewSQLLyricsRepo(db, dialect.Postgres{})
+
+	mock.ExpectQuery(`SELECT id, song_id, verse_number, text FROM lyrics WHERE song_id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "song_id", "verse_number", "text"}).
+			AddRow(1, 1, 1, "hello"))
+
+	verses, err := r.List(context.Background(), Filter{"song_id": uint(1)}, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, verses, 1)
+	require.Equal(t, "hello", verses[0].Text)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,33 @@
+// Package repo defines the per-entity repository interfaces that the
+// em4-gen tool (cmd/em4-gen) generates implementations for. Services should
+// depend on these narrow interfaces instead of the storage.Storage
+// god-object so they only pull in the entity they actually work with.
+package repo
+
+import (
+	"context"
+
+	"em4/internal/model"
+)
+
+// Filter is a column-name-to-value equality filter passed to List. It maps
+// directly onto a squirrel sq.Eq.
+type Filter map[string]interface{}
+
+// SongRepo is the generated repository for model.Song.
+type SongRepo interface {
+	Insert(ctx context.Context, song model.Song) (uint, error)
+	Update(ctx context.Context, id uint, song model.Song) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*model.Song, error)
+	List(ctx context.Context, filter Filter, limit, offset int) ([]model.Song, error)
+}
+
+// LyricsRepo is the generated repository for model.Lyrics.
+type LyricsRepo interface {
+	Insert(ctx context.Context, lyrics model.Lyrics) (uint, error)
+	Update(ctx context.Context, id uint, lyrics model.Lyrics) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*model.Lyrics, error)
+	List(ctx context.Context, filter Filter, limit, offset int) ([]model.Lyrics, error)
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"em4/internal/model"
+	"em4/internal/storage/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSongRepo_Insert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := NewSQLSongRepo(db, dialect.Postgres{})
+
+	song := model.Song{Group: "Muse", Name: "Starlight", ReleaseDate: time.Now(), InsertedAt: time.Now()}
+	mock.ExpectQuery(`INSERT INTO songs \(group_name,name,link,release_date,inserted_at\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING id`).
+		WithArgs(song.Group, song.Name, song.Link, song.ReleaseDate, song.InsertedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	id, err := r.Insert(context.Background(), song)
+	require.NoError(t, err)
+	require.Equal(t, uint(1), id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLSongRepo_FindByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := NewSQLSongRepo(db, dialect.Postgres{})
+
+	releaseDate := time.Now()
+	mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM songs WHERE id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "name", "link", "release_date", "inserted_at"}).
+			AddRow(1, "Muse", "Starlight", "", releaseDate, releaseDate))
+
+	song, err := r.FindByID(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, "Starlight", song.Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLSongRepo_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	r := NewSQLSongRepo(db, dialect.Postgres{})
+
+	mock.ExpectExec(`DELETE FROM songs WHERE id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, r.Delete(context.Background(), 1))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// scanTimeLayouts are tried in order when a driver hands back a timestamp
+// as text instead of a native time.Time. lib/pq and pgx scan timestamptz
+// columns straight into time.Time, but go-sql-driver/mysql (without
+// parseTime=true in its DSN) and mattn/go-sqlite3 return the column as a
+// string/[]byte in one of these formats.
+var scanTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ScanTime is a sql.Scanner that accepts a timestamp column regardless of
+// whether the driver returns it as a time.Time or as text, so GetSong,
+// GetAllSongs and the generated repositories in internal/storage/repo work
+// the same way against Postgres, MySQL and SQLite.
+type ScanTime struct {
+	time.Time
+}
+
+func (t *ScanTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("ScanTime: unsupported source type %T", src)
+	}
+}
+
+func (t *ScanTime) parse(s string) error {
+	var err error
+	for _, layout := range scanTimeLayouts {
+		var parsed time.Time
+		if parsed, err = time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("ScanTime: parse %q as a timestamp: %w", s, err)
+}
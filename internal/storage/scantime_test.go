@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTime(t *testing.T) {
+	native := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	t.Run("NativeTime", func(t *testing.T) {
+		var got ScanTime
+		require.NoError(t, got.Scan(native))
+		assert.True(t, native.Equal(got.Time))
+	})
+
+	t.Run("RFC3339String", func(t *testing.T) {
+		var got ScanTime
+		require.NoError(t, got.Scan("2006-01-02T15:04:05Z"))
+		assert.True(t, native.Equal(got.Time))
+	})
+
+	t.Run("MySQLDatetimeBytes", func(t *testing.T) {
+		var got ScanTime
+		require.NoError(t, got.Scan([]byte("2006-01-02 15:04:05")))
+		assert.True(t, native.Equal(got.Time))
+	})
+
+	t.Run("DateOnly", func(t *testing.T) {
+		var got ScanTime
+		require.NoError(t, got.Scan("2006-01-02"))
+		assert.True(t, got.Time.Equal(time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		var got ScanTime
+		require.NoError(t, got.Scan(nil))
+		assert.True(t, got.Time.IsZero())
+	})
+
+	t.Run("Unparseable", func(t *testing.T) {
+		var got ScanTime
+		require.Error(t, got.Scan("not-a-time"))
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		var got ScanTime
+		require.Error(t, got.Scan(42))
+	})
+}
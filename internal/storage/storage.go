@@ -4,121 +4,146 @@ import (
 	"context"
 	"database/sql"
 	storage "em4/internal"
+	"em4/internal/migrations"
 	"em4/internal/model"
+	"em4/internal/storage/dialect"
 	"errors"
 	"fmt"
-	"log"
 
-	"gorm.io/gorm"
+	sq "github.com/Masterminds/squirrel"
 
-	"github.com/jackc/pgx/v4/pgxpool"
-	"gorm.io/driver/postgres"
+	// database/sql driver registrations for newSQLStorage's sql.Open calls.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-// first type
-type PostgresStorage struct {
-	db *sql.DB
+// SQLStorage is the database/sql-backed Storage implementation. It is
+// parameterized by a dialect so the exact same query-building code runs
+// against Postgres, MySQL and SQLite; only placeholder style, how the id of
+// an inserted row is recovered, identifier quoting and the current-timestamp
+// expression differ between them.
+type SQLStorage struct {
+	db      *sql.DB
+	dialect dialect.Dialect
+	tracer  Tracer
+
+	lyricsProvider LyricsProvider
+	enrichWorkers  int
+	enrichQueue    chan enrichJob
 }
 
-func NewPostgresStorage(path string) (*PostgresStorage, error) {
-	db, err := sql.Open("postgres", path)
-	if err != nil {
-		return nil, err
-	}
+var _ storage.Storage = (*SQLStorage)(nil)
 
-	return &PostgresStorage{
-		db: db,
-	}, nil
+// NewPostgresStorage opens a Postgres connection via database/sql.
+func NewPostgresStorage(path string, opts ...Option) (*SQLStorage, error) {
+	return newSQLStorage("postgres", path, dialect.Postgres{}, opts)
 }
 
-func (s *PostgresStorage) Close() error {
-	if s.db != nil {
-		return s.db.Close()
-	}
-	return nil
+// NewMySQLStorage opens a MySQL connection via database/sql.
+func NewMySQLStorage(path string, opts ...Option) (*SQLStorage, error) {
+	return newSQLStorage("mysql", path, dialect.MySQL{}, opts)
 }
 
-// second type
-type ORMPostgresStorage struct {
-	db *gorm.DB
+// NewSQLiteStorage opens a SQLite connection via database/sql.
+func NewSQLiteStorage(path string, opts ...Option) (*SQLStorage, error) {
+	return newSQLStorage("sqlite3", path, dialect.SQLite{}, opts)
 }
 
-func NewORMPostgresStorage(path string) (*ORMPostgresStorage, error) {
-	db, err := gorm.Open(postgres.Open(path), &gorm.Config{})
+func newSQLStorage(driverName, path string, d dialect.Dialect, opts []Option) (*SQLStorage, error) {
+	db, err := sql.Open(driverName, path)
 	if err != nil {
 		return nil, err
 	}
-	db.AutoMigrate(&model.Song{}, &model.Lyrics{})
 
-	return &ORMPostgresStorage{
-		db: db,
-	}, nil
+	s := &SQLStorage{
+		db:      db,
+		dialect: d,
+		tracer:  noopTracer{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.startEnrichWorkers()
+
+	return s, nil
+}
+
+// NewSQLStorageForTest builds an SQLStorage around an already-open db,
+// bypassing sql.Open. It exists so packages outside internal/storage (e.g.
+// internal/api) can exercise a real Storage backed by sqlmock without
+// reaching into SQLStorage's unexported fields.
+func NewSQLStorageForTest(db *sql.DB, d dialect.Dialect) (*SQLStorage, error) {
+	return &SQLStorage{db: db, dialect: d, tracer: noopTracer{}}, nil
 }
 
-func (s *ORMPostgresStorage) Close() error {
+func (s *SQLStorage) Close() error {
 	if s.db != nil {
-		sqlDB, err := s.db.DB()
-		if err != nil {
-			return err
-		}
-		return sqlDB.Close()
+		return s.db.Close()
 	}
 	return nil
 }
 
-// third type
-type PGXStorage struct {
-	db *pgxpool.Pool
+// DB returns the underlying *sql.DB so callers outside this package (e.g.
+// the generated repo.SQLSongRepo/repo.SQLLyricsRepo) can share the same
+// connection pool and dialect instead of opening their own.
+func (s *SQLStorage) DB() *sql.DB {
+	return s.db
 }
 
-func NewPGXStorage(path string) (*PGXStorage, error) {
-	config, _ := pgxpool.ParseConfig(path)
-	config.MaxConns = 10
+// Dialect returns the dialect SQLStorage was constructed with.
+func (s *SQLStorage) Dialect() dialect.Dialect {
+	return s.dialect
+}
 
-	pool, err := pgxpool.ConnectConfig(context.Background(), config)
-	if err != nil {
-		log.Fatal("Unable to create connection pool:", err)
+// Migrate opens its own connection to path and runs the goose migrations in
+// direction ("up", "down" or "status") up to target. With target 0, "up"
+// applies every pending migration and "down" reverts only the most recently
+// applied one. It is meant to be called from the em4 CLI, not from
+// long-running server code.
+//
+// The migrations under internal/migrations are Postgres-specific DDL
+// (BIGSERIAL, TIMESTAMPTZ), so driver must be "postgres" or "" (which
+// defaults to it); any other driver is rejected with
+// ErrUnsupportedMigrationDriver rather than silently running Postgres DDL
+// against a MySQL or SQLite connection.
+func Migrate(ctx context.Context, driver, path, direction string, target int64) error {
+	if driver != "" && driver != "postgres" {
+		return fmt.Errorf("migrate: %w: %q", storage.ErrUnsupportedMigrationDriver, driver)
 	}
 
-	return &PGXStorage{
-		db: pool,
-	}, nil
-}
+	db, err := sql.Open("postgres", path)
+	if err != nil {
+		return fmt.Errorf("migrate: open: %w", err)
+	}
+	defer db.Close()
 
-func (s *PGXStorage) Close() error {
-	if s.db == nil {
-		return nil
+	if err := migrations.Run(ctx, db, direction, target); err != nil {
+		return fmt.Errorf("migrate: %w", err)
 	}
-	s.db.Close()
 	return nil
 }
 
-// examples:
-func (s *PGXStorage) GetSong(songID uint) (uint, error) {
-	err := s.db.QueryRow(context.Background(), "SELECT id FROM songs WHERE id = $1", songID).Scan(&songID)
-	return songID, err
+// EnsureSchema runs every pending migration against path. It is called from
+// cmd/main.go on startup so the schema is always current before the rest of
+// run() touches the database. Like Migrate, it only supports driver
+// "postgres" (or "") and returns ErrUnsupportedMigrationDriver otherwise.
+func EnsureSchema(ctx context.Context, driver, path string) error {
+	return Migrate(ctx, driver, path, "up", 0)
 }
 
-func (s *ORMPostgresStorage) GetSong(songID uint) (*model.Song, error) {
-	var song model.Song
-	err := s.db.First(&song, songID).Error
-	return &song, err
-}
-
-// main db
-func (s *PostgresStorage) WithTransaction(fn func(tx *sql.Tx) error) error {
-	tx, err := s.db.Begin()
+func (s *SQLStorage) WithTransaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	if err := fn(tx); err != nil {
-		err = tx.Rollback()
-		if err != nil {
-			return err
+	if fnErr := fn(tx); fnErr != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", fnErr, rbErr)
 		}
 
-		return err
+		return fnErr
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -128,49 +153,132 @@ func (s *PostgresStorage) WithTransaction(fn func(tx *sql.Tx) error) error {
 	return nil
 }
 
-func (s *PostgresStorage) AddSong(song model.Song, verses []string) (uint, error) {
+// builder returns a squirrel StatementBuilder using this storage's dialect
+// placeholder style.
+func (s *SQLStorage) builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(s.dialect.Placeholder())
+}
+
+// traced runs fn wrapped in a span for query, reporting the row count and
+// error fn returns to s.tracer.
+func (s *SQLStorage) traced(ctx context.Context, query string, fn func() (int, error)) (int, error) {
+	tracer := s.tracer
+	if tracer == nil {
+		tracer = noopTracer{}
+	}
+
+	span := tracer.StartSpan(ctx, query)
+	rows, err := fn()
+	span.End(rows, err)
+	return rows, err
+}
+
+// AddSong inserts song and its verses. If verses is empty and a
+// LyricsProvider was configured via WithLyricsProvider, it is asked to
+// fetch the lyrics: synchronously, persisted in the same transaction as
+// the song row, unless WithAsyncEnrichment was also set, in which case the
+// fetch happens on a background worker and only its verses are written
+// back with UpdateSong once it completes.
+func (s *SQLStorage) AddSong(ctx context.Context, song model.Song, verses []string) (uint, error) {
 	var songID uint
 
-	if err := s.WithTransaction(func(tx *sql.Tx) error {
-		err := tx.QueryRow(
-			`INSERT INTO songs (group_name, name, link, release_date, inserted_at) 
-             VALUES ($1, $2, $3, $4, NOW()) 
-             RETURNING id`,
-			song.Group, song.Name, song.Link, song.ReleaseDate,
-		).Scan(&songID)
+	enrichAsync := len(verses) == 0 && s.lyricsProvider != nil && s.enrichQueue != nil
+	if len(verses) == 0 && s.lyricsProvider != nil && !enrichAsync {
+		fetched, releaseDate, link, err := s.lyricsProvider.Fetch(ctx, song.Group, song.Name)
 		if err != nil {
-			return err
+			return 0, fmt.Errorf("fetch lyrics: %w", err)
+		}
+		verses = fetched
+		if song.ReleaseDate.IsZero() {
+			song.ReleaseDate = releaseDate
+		}
+		if song.Link == "" {
+			song.Link = link
+		}
+	}
+
+	if err := s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		insertSong := s.builder().
+			Insert(s.dialect.QuoteIdent("songs")).
+			Columns("group_name", "name", "link", "release_date", "inserted_at").
+			Values(song.Group, song.Name, song.Link, song.ReleaseDate, sq.Expr(s.dialect.Now()))
+
+		if s.dialect.SupportsReturning() {
+			query, args, err := insertSong.Suffix("RETURNING id").ToSql()
+			if err != nil {
+				return err
+			}
+			if _, err := s.traced(ctx, query, func() (int, error) {
+				return 1, tx.QueryRowContext(ctx, query, args...).Scan(&songID)
+			}); err != nil {
+				return err
+			}
+		} else {
+			query, args, err := insertSong.ToSql()
+			if err != nil {
+				return err
+			}
+			var result sql.Result
+			if _, err := s.traced(ctx, query, func() (int, error) {
+				var execErr error
+				result, execErr = tx.ExecContext(ctx, query, args...)
+				return 1, execErr
+			}); err != nil {
+				return err
+			}
+			lastID, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			songID = uint(lastID)
 		}
 
 		for i, verse := range verses {
-			_, err = tx.Exec(
-				`INSERT INTO lyrics (song_id, verse_number, text) 
-                 VALUES ($1, $2, $3)`,
-				songID, i+1, verse,
-			)
+			query, args, err := s.builder().
+				Insert(s.dialect.QuoteIdent("lyrics")).
+				Columns("song_id", "verse_number", "text").
+				Values(songID, i+1, verse).
+				ToSql()
 			if err != nil {
 				return err
 			}
+			if _, err := s.traced(ctx, query, func() (int, error) {
+				_, err := tx.ExecContext(ctx, query, args...)
+				return 1, err
+			}); err != nil {
+				return err
+			}
 		}
 		return nil
 	}); err != nil {
 		return 0, err
 	}
 
+	if enrichAsync {
+		s.enrichQueue <- enrichJob{songID: songID, group: song.Group, name: song.Name}
+	}
+
 	return songID, nil
 }
 
-func (s *PostgresStorage) DeleteSong(songID uint) error {
-	result, err := s.db.Exec(
-		`DELETE FROM songs WHERE id = $1`,
-		songID,
-	)
+func (s *SQLStorage) DeleteSong(ctx context.Context, songID uint) error {
+	query, args, err := s.builder().
+		Delete(s.dialect.QuoteIdent("songs")).
+		Where(sq.Eq{"id": songID}).
+		ToSql()
 	if err != nil {
 		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	var rowsAffected int64
+	if _, err := s.traced(ctx, query, func() (int, error) {
+		result, err := s.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		rowsAffected, err = result.RowsAffected()
+		return int(rowsAffected), err
+	}); err != nil {
 		return err
 	}
 
@@ -181,110 +289,283 @@ func (s *PostgresStorage) DeleteSong(songID uint) error {
 	return nil
 }
 
-func (s *PostgresStorage) GetSong(songID uint) (*model.Song, error) {
+func (s *SQLStorage) GetSong(ctx context.Context, songID uint) (*model.Song, error) {
+	query, args, err := s.builder().
+		Select("id", "group_name", "name", "link", "release_date", "inserted_at").
+		From(s.dialect.QuoteIdent("songs")).
+		Where(sq.Eq{"id": songID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
 	song := &model.Song{}
-	err := s.db.QueryRow(
-		`SELECT id, group_name, name, link, release_date, inserted_at 
-         FROM songs 
-         WHERE id = $1`,
-		songID,
-	).Scan(
-		&song.ID, &song.Group, &song.Name, &song.Link, &song.ReleaseDate, &song.InsertedAt,
-	)
+	var releaseDate, insertedAt ScanTime
+	_, err = s.traced(ctx, query, func() (int, error) {
+		scanErr := s.db.QueryRowContext(ctx, query, args...).Scan(
+			&song.ID, &song.Group, &song.Name, &song.Link, &releaseDate, &insertedAt,
+		)
+		if scanErr != nil {
+			return 0, scanErr
+		}
+		return 1, nil
+	})
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, storage.ErrSongNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	song.ReleaseDate, song.InsertedAt = releaseDate.Time, insertedAt.Time
 
 	return song, nil
 }
 
-func (s *PostgresStorage) UpdateSong(songID uint, updates model.SongUpdate) error {
-	songQuery, songArgs, err := s.buildUpdateSongQuery(songID, updates)
-	if err != nil && len(updates.Verses) == 0 {
-		return err
+func (s *SQLStorage) GetLyrics(ctx context.Context, songID uint, limit, offset int) ([]model.Lyrics, error) {
+	sel := s.builder().
+		Select("id", "song_id", "verse_number", "text").
+		From(s.dialect.QuoteIdent("lyrics")).
+		Where(sq.Eq{"song_id": songID}).
+		OrderBy("verse_number")
+
+	if limit > 0 {
+		sel = sel.Limit(uint64(limit))
+	}
+	if offset > 0 {
+		sel = sel.Offset(uint64(offset))
+	}
+
+	query, args, err := sel.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.queryLyrics(ctx, query, args)
+}
+
+func (s *SQLStorage) GetAllSongLyrics(ctx context.Context, songID uint) ([]model.Lyrics, error) {
+	query, args, err := s.builder().
+		Select("id", "song_id", "verse_number", "text").
+		From(s.dialect.QuoteIdent("lyrics")).
+		Where(sq.Eq{"song_id": songID}).
+		OrderBy("verse_number").
+		ToSql()
+	if err != nil {
+		return nil, err
 	}
 
-	if songQuery != "" {
-		_, err := s.db.Exec(songQuery, songArgs...)
+	return s.queryLyrics(ctx, query, args)
+}
+
+func (s *SQLStorage) queryLyrics(ctx context.Context, query string, args []interface{}) ([]model.Lyrics, error) {
+	var lyrics []model.Lyrics
+	if _, err := s.traced(ctx, query, func() (int, error) {
+		rows, err := s.db.QueryContext(ctx, query, args...)
 		if err != nil {
-			return fmt.Errorf("failed to update song: %w", err)
+			return 0, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var l model.Lyrics
+			if err := rows.Scan(&l.ID, &l.SongID, &l.VerseNumber, &l.Text); err != nil {
+				return len(lyrics), err
+			}
+			lyrics = append(lyrics, l)
 		}
+		return len(lyrics), rows.Err()
+	}); err != nil {
+		return nil, err
 	}
 
-	verseQueries := s.buildUpdateVerseQuery(songID, updates.Verses)
+	return lyrics, nil
+}
 
-	for _, q := range verseQueries {
-		_, err := s.db.Exec(q.Query, q.Args...)
+func (s *SQLStorage) GetAllSongs(ctx context.Context, filters map[string]string, limit, offset int) ([]model.Song, error) {
+	sel := s.builder().
+		Select("id", "group_name", "name", "link", "release_date", "inserted_at").
+		From(s.dialect.QuoteIdent("songs"))
+
+	if group, ok := filters["group"]; ok && group != "" {
+		sel = sel.Where(sq.Eq{"group_name": group})
+	}
+	if name, ok := filters["name"]; ok && name != "" {
+		sel = sel.Where(sq.Eq{"name": name})
+	}
+	if releasedAfter, ok := filters["released_after"]; ok && releasedAfter != "" {
+		sel = sel.Where(sq.Gt{"release_date": releasedAfter})
+	}
+
+	if limit > 0 {
+		sel = sel.Limit(uint64(limit))
+	}
+	if offset > 0 {
+		sel = sel.Offset(uint64(offset))
+	}
+
+	query, args, err := sel.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var songs []model.Song
+	if _, err := s.traced(ctx, query, func() (int, error) {
+		rows, err := s.db.QueryContext(ctx, query, args...)
 		if err != nil {
-			return fmt.Errorf("failed to update verse: %w", err)
+			return 0, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var song model.Song
+			var releaseDate, insertedAt ScanTime
+			if err := rows.Scan(&song.ID, &song.Group, &song.Name, &song.Link, &releaseDate, &insertedAt); err != nil {
+				return len(songs), err
+			}
+			song.ReleaseDate, song.InsertedAt = releaseDate.Time, insertedAt.Time
+			songs = append(songs, song)
 		}
+		return len(songs), rows.Err()
+	}); err != nil {
+		return nil, err
 	}
-	return nil
+
+	return songs, nil
 }
 
-func (s *PostgresStorage) buildUpdateSongQuery(songID uint, updates model.SongUpdate) (string, []interface{}, error) {
-	query := "UPDATE songs SET "
-	var args []interface{}
-	argIndex := 1
-	updatesApplied := false
+// UpdateSong applies updates to songID. The song row (if any scalar fields
+// are set) and all verse updates commit or roll back together: a failure
+// partway through must not leave lyrics inconsistent with the song row.
+func (s *SQLStorage) UpdateSong(ctx context.Context, songID uint, updates model.SongUpdate) error {
+	if updates.IsEmpty() {
+		return storage.ErrEmptyUpdate
+	}
+
+	return s.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if updates.HasSongFields() {
+			songQuery, songArgs, err := s.buildUpdateSongQuery(songID, updates)
+			if err != nil {
+				return fmt.Errorf("failed to build song update: %w", err)
+			}
+			if _, err := s.traced(ctx, songQuery, func() (int, error) {
+				_, err := tx.ExecContext(ctx, songQuery, songArgs...)
+				return 1, err
+			}); err != nil {
+				return fmt.Errorf("failed to update song: %w", err)
+			}
+		}
+
+		for verseNumber, text := range updates.Verses {
+			if err := s.upsertVerse(ctx, tx, songID, verseNumber, text); err != nil {
+				return fmt.Errorf("failed to update verse: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// buildUpdateSongQuery assumes updates.HasSongFields() is true; callers must
+// check that before calling it.
+func (s *SQLStorage) buildUpdateSongQuery(songID uint, updates model.SongUpdate) (string, []interface{}, error) {
+	update := s.builder().Update(s.dialect.QuoteIdent("songs")).Where(sq.Eq{"id": songID})
 
 	if updates.Group != "" {
-		query += fmt.Sprintf("group_name = $%d, ", argIndex)
-		args = append(args, updates.Group)
-		argIndex++
-		updatesApplied = true
+		update = update.Set("group_name", updates.Group)
 	}
 	if updates.Name != "" {
-		query += fmt.Sprintf("name = $%d, ", argIndex)
-		args = append(args, updates.Name)
-		argIndex++
-		updatesApplied = true
+		update = update.Set("name", updates.Name)
 	}
 	if updates.ReleaseDate != "" {
-		query += fmt.Sprintf("release_date = $%d, ", argIndex)
-		args = append(args, updates.ReleaseDate)
-		argIndex++
-		updatesApplied = true
+		update = update.Set("release_date", updates.ReleaseDate)
 	}
 	if updates.Link != "" {
-		query += fmt.Sprintf("link = $%d, ", argIndex)
-		args = append(args, updates.Link)
-		argIndex++
-		updatesApplied = true
-	}
-	if !updatesApplied {
-		return "", nil, fmt.Errorf("no valid fields to update")
+		update = update.Set("link", updates.Link)
 	}
 
-	query = query[:len(query)-2]
-	query += fmt.Sprintf(" WHERE id = $%d RETURNING id", argIndex)
-	args = append(args, songID)
-	return query, args, nil
+	return update.ToSql()
 }
 
-func (s *PostgresStorage) buildUpdateVerseQuery(songID uint, verses map[uint]string) []struct {
-	Query string
-	Args  []interface{}
-} {
-	var queries []struct {
-		Query string
-		Args  []interface{}
-	}
-
-	for verseNumber, text := range verses {
-		query := "UPDATE lyrics SET text = $1 WHERE song_id = $2 AND verse_number = $3"
-		args := []interface{}{text, songID, verseNumber}
-		queries = append(queries, struct {
-			Query string
-			Args  []interface{}
-		}{
-			Query: query,
-			Args:  args,
+// upsertVerseSavepoint names the SAVEPOINT upsertVerse wraps its INSERT
+// attempt in. Postgres aborts the rest of the transaction after any failed
+// statement, so without a savepoint to roll back to, the retry UPDATE below
+// would itself fail instead of reconciling the race.
+const upsertVerseSavepoint = "upsert_verse"
+
+// upsertVerse writes verseNumber's text for songID. AddSong only inserts
+// verses it's handed up front, so a verse added later by UpdateSong (most
+// notably the async enrichment path in lyrics.go, which has none yet) has
+// no existing row to UPDATE; in that case this falls back to an INSERT. If
+// that INSERT loses a race with a concurrent writer of the same verse (the
+// UNIQUE(song_id, verse_number) constraint from migration 0001 rejects it),
+// one more UPDATE reconciles with whichever write landed first.
+func (s *SQLStorage) upsertVerse(ctx context.Context, tx *sql.Tx, songID, verseNumber uint, text string) error {
+	rowsAffected, err := s.updateVerse(ctx, tx, songID, verseNumber, text)
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	execTraced := func(stmt string) error {
+		_, err := s.traced(ctx, stmt, func() (int, error) {
+			_, err := tx.ExecContext(ctx, stmt)
+			return 0, err
 		})
+		return err
+	}
+
+	if err := execTraced("SAVEPOINT " + upsertVerseSavepoint); err != nil {
+		return err
 	}
 
-	return queries
+	insertQuery, insertArgs, err := s.builder().
+		Insert(s.dialect.QuoteIdent("lyrics")).
+		Columns("song_id", "verse_number", "text").
+		Values(songID, verseNumber, text).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, insertErr := s.traced(ctx, insertQuery, func() (int, error) {
+		_, err := tx.ExecContext(ctx, insertQuery, insertArgs...)
+		return 1, err
+	})
+	if insertErr == nil {
+		return execTraced("RELEASE SAVEPOINT " + upsertVerseSavepoint)
+	}
+
+	if err := execTraced("ROLLBACK TO SAVEPOINT " + upsertVerseSavepoint); err != nil {
+		return err
+	}
+
+	rowsAffected, err = s.updateVerse(ctx, tx, songID, verseNumber, text)
+	if err != nil || rowsAffected == 0 {
+		return insertErr
+	}
+	return nil
+}
+
+// updateVerse runs the UPDATE half of upsertVerse and reports how many rows
+// it matched, so callers can tell "no such verse yet" from a write error.
+func (s *SQLStorage) updateVerse(ctx context.Context, tx *sql.Tx, songID, verseNumber uint, text string) (int64, error) {
+	query, args, err := s.builder().
+		Update(s.dialect.QuoteIdent("lyrics")).
+		Set("text", text).
+		Where(sq.Eq{"song_id": songID, "verse_number": verseNumber}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var rowsAffected int64
+	_, err = s.traced(ctx, query, func() (int, error) {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return 0, err
+		}
+		rowsAffected, err = result.RowsAffected()
+		return int(rowsAffected), err
+	})
+	return rowsAffected, err
 }
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	st "em4/internal"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"em4/internal/model"
+	"em4/internal/storage/dialect"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -19,8 +21,9 @@ func TestAddSong_Success(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	storage := &PostgresStorage{
-		db: db,
+	storage := &SQLStorage{
+		db:      db,
+		dialect: dialect.Postgres{},
 	}
 
 	song := model.Song{
@@ -33,19 +36,19 @@ func TestAddSong_Success(t *testing.T) {
 
 	mock.ExpectBegin()
 
-	mock.ExpectQuery(`INSERT INTO songs \(group_name, name, link, release_date, inserted_at\) VALUES \(\$1, \$2, \$3, \$4, NOW\(\)\) RETURNING id`).
+	mock.ExpectQuery(`INSERT INTO "songs" \(group_name,name,link,release_date,inserted_at\) VALUES \(\$1,\$2,\$3,\$4,NOW\(\)\) RETURNING id`).
 		WithArgs(song.Group, song.Name, song.Link, song.ReleaseDate).
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 	for i, verse := range verses {
-		mock.ExpectExec(`INSERT INTO lyrics \(song_id, verse_number, text\) VALUES \(\$1, \$2, \$3\)`).
+		mock.ExpectExec(`INSERT INTO "lyrics" \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\)`).
 			WithArgs(1, i+1, verse).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 	}
 
 	mock.ExpectCommit()
 
-	songID, err := storage.AddSong(song, verses)
+	songID, err := storage.AddSong(context.Background(), song, verses)
 	require.NoError(t, err)
 
 	assert.Equal(t, uint(1), songID)
@@ -57,7 +60,10 @@ func TestGetSong(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	storage := &PostgresStorage{db: db}
+	storage := &SQLStorage{
+		db:      db,
+		dialect: dialect.Postgres{},
+	}
 	songID := uint(1)
 
 	expectedSong := &model.Song{
@@ -70,34 +76,34 @@ func TestGetSong(t *testing.T) {
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM songs WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "group_name", "name", "link", "release_date", "inserted_at"}).
 				AddRow(expectedSong.ID, expectedSong.Group, expectedSong.Name, expectedSong.Link, expectedSong.ReleaseDate, expectedSong.InsertedAt))
 
-		song, err := storage.GetSong(songID)
+		song, err := storage.GetSong(context.Background(), songID)
 		require.NoError(t, err)
 		require.Equal(t, expectedSong, song)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("NotFound", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM songs WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnError(sql.ErrNoRows)
 
-		song, err := storage.GetSong(songID)
+		song, err := storage.GetSong(context.Background(), songID)
 		require.ErrorIs(t, err, st.ErrSongNotFound)
 		require.Nil(t, song)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("DatabaseError", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM songs WHERE id = \$1`).
+		mock.ExpectQuery(`SELECT id, group_name, name, link, release_date, inserted_at FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnError(errors.New("mocked database error"))
 
-		song, err := storage.GetSong(songID)
+		song, err := storage.GetSong(context.Background(), songID)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "mocked database error")
 		require.Nil(t, song)
@@ -110,37 +116,182 @@ func TestDeleteSong(t *testing.T) {
 	require.NoError(t, err)
 	defer db.Close()
 
-	storage := &PostgresStorage{db: db}
+	storage := &SQLStorage{
+		db:      db,
+		dialect: dialect.Postgres{},
+	}
 	songID := uint(1)
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectExec(`DELETE FROM songs WHERE id = \$1`).
+		mock.ExpectExec(`DELETE FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
 
-		err := storage.DeleteSong(songID)
+		err := storage.DeleteSong(context.Background(), songID)
 		require.NoError(t, err)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("NotFound", func(t *testing.T) {
-		mock.ExpectExec(`DELETE FROM songs WHERE id = \$1`).
+		mock.ExpectExec(`DELETE FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnResult(sqlmock.NewResult(0, 0))
 
-		err := storage.DeleteSong(songID)
+		err := storage.DeleteSong(context.Background(), songID)
 		require.ErrorIs(t, err, st.ErrSongNotFound)
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("DatabaseError", func(t *testing.T) {
-		mock.ExpectExec(`DELETE FROM songs WHERE id = \$1`).
+		mock.ExpectExec(`DELETE FROM "songs" WHERE id = \$1`).
 			WithArgs(songID).
 			WillReturnError(errors.New("mocked database error"))
 
-		err := storage.DeleteSong(songID)
+		err := storage.DeleteSong(context.Background(), songID)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "mocked database error")
 		require.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUpdateSong(t *testing.T) {
+	songID := uint(1)
+
+	t.Run("EmptyUpdate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{})
+		require.ErrorIs(t, err, st.ErrEmptyUpdate)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SongAndVerses_Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "songs" SET name = \$1 WHERE id = \$2`).
+			WithArgs("New Name", songID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{
+			Name:   "New Name",
+			Verses: map[uint]string{1: "New Verse"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VerseFailure_RollsBackSongUpdate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "songs" SET name = \$1 WHERE id = \$2`).
+			WithArgs("New Name", songID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnError(errors.New("mocked database error"))
+		mock.ExpectRollback()
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{
+			Name:   "New Name",
+			Verses: map[uint]string{1: "New Verse"},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to update verse")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VerseNotYetInserted_FallsBackToInsert", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT upsert_verse`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO "lyrics" \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\)`).
+			WithArgs(songID, uint(1), "New Verse").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT upsert_verse`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{
+			Verses: map[uint]string{1: "New Verse"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VerseInsertRacedConcurrentWriter_RetriesUpdate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT upsert_verse`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO "lyrics" \(song_id,verse_number,text\) VALUES \(\$1,\$2,\$3\)`).
+			WithArgs(songID, uint(1), "New Verse").
+			WillReturnError(errors.New("UNIQUE constraint failed: lyrics.song_id, lyrics.verse_number"))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT upsert_verse`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{
+			Verses: map[uint]string{1: "New Verse"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("VersesOnly_Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		storage := &SQLStorage{db: db, dialect: dialect.Postgres{}}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "lyrics" SET text = \$1 WHERE song_id = \$2 AND verse_number = \$3`).
+			WithArgs("New Verse", songID, uint(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = storage.UpdateSong(context.Background(), songID, model.SongUpdate{
+			Verses: map[uint]string{1: "New Verse"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,39 @@
+package storage
+
+import "context"
+
+// Tracer is an OpenTelemetry-style hook point: SQLStorage calls it around
+// every query so callers can emit spans without this package depending on
+// any particular tracing SDK.
+type Tracer interface {
+	// StartSpan is called before a query runs. The returned Span's End
+	// must be called once the query completes.
+	StartSpan(ctx context.Context, query string) Span
+}
+
+// Span receives the outcome of the query it was started for.
+type Span interface {
+	// End is called with the number of rows the query produced or
+	// affected (best-effort; 0 when unknown) and the error it returned,
+	// if any.
+	End(rowCount int, err error)
+}
+
+type noopTracer struct{}
+
+func (noopTracer) StartSpan(context.Context, string) Span { return noopSpan{} }
+
+type noopSpan struct{}
+
+func (noopSpan) End(int, error) {}
+
+// Option configures a SQLStorage at construction time.
+type Option func(*SQLStorage)
+
+// WithTracer attaches t to a SQLStorage so every query it runs is wrapped
+// in a span carrying the SQL statement and the resulting row count.
+func WithTracer(t Tracer) Option {
+	return func(s *SQLStorage) {
+		s.tracer = t
+	}
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"em4/internal/storage/dialect"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTracer struct {
+	spans []string
+}
+
+type fakeSpan struct {
+	tracer   *fakeTracer
+	query    string
+	rowCount int
+	err      error
+}
+
+func (t *fakeTracer) StartSpan(ctx context.Context, query string) Span {
+	return &fakeSpan{tracer: t, query: query}
+}
+
+func (s *fakeSpan) End(rowCount int, err error) {
+	s.rowCount = rowCount
+	s.err = err
+	s.tracer.spans = append(s.tracer.spans, s.query)
+}
+
+func TestWithTracer_EmitsSpanPerQuery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	tracer := &fakeTracer{}
+	s := &SQLStorage{db: db, dialect: dialect.Postgres{}, tracer: tracer}
+
+	mock.ExpectExec(`DELETE FROM "songs" WHERE id = \$1`).
+		WithArgs(uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = s.DeleteSong(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Len(t, tracer.spans, 1)
+	assert.Contains(t, tracer.spans[0], `DELETE FROM "songs"`)
+}